@@ -0,0 +1,172 @@
+package chatgpt
+
+import (
+	"context"
+
+	"github.com/otiai10/openaigo/chatgpt/tokens"
+)
+
+// TruncatePolicy shrinks conv so that its estimated token count plus
+// c.MaxTokens (c.MaxCompletionTokens on o1-series models) fits within
+// c.TokenBudget. overBy is how many tokens the
+// estimate exceeds the budget by. Implementations should keep matching
+// tool_call/tool message pairs together: OpenAI rejects a "tool" message
+// whose originating assistant tool_call was dropped.
+type TruncatePolicy func(ctx context.Context, c *Client, conv []Message, overBy int) ([]Message, error)
+
+// truncateToBudget estimates conv's token usage with c.TokenBudget and, if
+// it together with c.MaxTokens would exceed it, applies c.TruncatePolicy
+// (or DropOldest by default, or SummarizeOldest when c.Summarize is set).
+func (c *Client) truncateToBudget(ctx context.Context, conv []Message) ([]Message, error) {
+	if c.TokenBudget <= 0 {
+		return conv, nil
+	}
+
+	enc := tokens.NewEncoder(tokens.EncodingForModel(c.Model))
+	estimate := estimateTokens(enc, conv)
+	// o1-series models always have MaxTokens == 0 (see validateO1); their
+	// reserved completion space is MaxCompletionTokens instead.
+	reserved := c.MaxTokens
+	if isO1Model(c.Model) {
+		reserved = c.MaxCompletionTokens
+	}
+	overBy := estimate + reserved - c.TokenBudget
+	if overBy <= 0 {
+		return conv, nil
+	}
+
+	policy := c.TruncatePolicy
+	if policy == nil {
+		policy = DropOldest
+		if c.Summarize {
+			policy = SummarizeOldest
+		}
+	}
+	return policy(ctx, c, conv, overBy)
+}
+
+func estimateTokens(enc *tokens.Encoder, conv []Message) int {
+	total := 0
+	for _, m := range conv {
+		// A small fixed overhead per message for role/name/function
+		// framing, on top of the content itself.
+		total += 4 + enc.Count(m.Content)
+	}
+	return total
+}
+
+// unit is the smallest block a TruncatePolicy may drop or keep as a whole.
+// Most messages are their own unit, but an assistant message that issued
+// ToolCalls is grouped together with every role:"tool" message answering
+// it, since OpenAI rejects a "tool" message whose originating tool_call
+// isn't present in the same request.
+type unit []Message
+
+// unitsOf groups conv into units, in order.
+func unitsOf(conv []Message) []unit {
+	var units []unit
+	for i := 0; i < len(conv); {
+		if conv[i].Role == "assistant" && len(conv[i].ToolCalls) > 0 {
+			j := i + 1
+			for j < len(conv) && conv[j].Role == "tool" {
+				j++
+			}
+			units = append(units, unit(conv[i:j]))
+			i = j
+			continue
+		}
+		units = append(units, unit(conv[i:i+1]))
+		i++
+	}
+	return units
+}
+
+// isSystem reports whether u is a lone system message.
+func (u unit) isSystem() bool {
+	return len(u) == 1 && u[0].Role == "system"
+}
+
+func (u unit) tokens(enc *tokens.Encoder) int {
+	total := 0
+	for _, m := range u {
+		total += 4 + enc.Count(m.Content)
+	}
+	return total
+}
+
+func flattenUnits(units []unit) []Message {
+	var out []Message
+	for _, u := range units {
+		out = append(out, u...)
+	}
+	return out
+}
+
+// DropOldest removes the oldest non-system units from conv until its
+// estimated token count has fallen by at least overBy, keeping every
+// system message in place regardless of its position and never splitting
+// an assistant tool_call from the tool messages answering it.
+func DropOldest(ctx context.Context, c *Client, conv []Message, overBy int) ([]Message, error) {
+	enc := tokens.NewEncoder(tokens.EncodingForModel(c.Model))
+
+	var kept []unit
+	removed := 0
+	for _, u := range unitsOf(conv) {
+		if removed < overBy && !u.isSystem() {
+			removed += u.tokens(enc)
+			continue
+		}
+		kept = append(kept, u)
+	}
+	return flattenUnits(kept), nil
+}
+
+// SummarizeOldest replaces as many of the oldest non-system units as
+// needed to free overBy tokens with a single synthetic message produced by
+// asking the model to summarize them. System messages found in that prefix
+// are never summarized away; they are kept in place, ahead of the synthetic
+// summary. A unit is never split: an assistant tool_call and the tool
+// messages answering it are summarized or kept together. The summary
+// itself is a role:"system" message, except on o1-series models, which
+// reject that role outright (see validateO1); there it is sent as "user"
+// instead.
+func SummarizeOldest(ctx context.Context, c *Client, conv []Message, overBy int) ([]Message, error) {
+	enc := tokens.NewEncoder(tokens.EncodingForModel(c.Model))
+
+	var dropped, keptSystem []unit
+	freed := 0
+	cut := 0
+	units := unitsOf(conv)
+	for cut < len(units) && freed < overBy {
+		u := units[cut]
+		if u.isSystem() {
+			keptSystem = append(keptSystem, u)
+		} else {
+			dropped = append(dropped, u)
+			freed += u.tokens(enc)
+		}
+		cut++
+	}
+	if len(dropped) == 0 {
+		return conv, nil
+	}
+
+	summarizer := &Client{Client: c.Client, Model: c.Model, StrictO1: c.StrictO1}
+	summary, _, err := summarizer.Chat(ctx, append(flattenUnits(dropped), User(summarizePrompt)))
+	if err != nil {
+		return conv, err
+	}
+
+	synthesize := System
+	if isO1Model(c.Model) {
+		synthesize = User
+	}
+
+	out := make([]Message, 0, len(flattenUnits(keptSystem))+1+len(flattenUnits(units[cut:])))
+	out = append(out, flattenUnits(keptSystem)...)
+	out = append(out, synthesize(summary[len(summary)-1].Content))
+	out = append(out, flattenUnits(units[cut:])...)
+	return out, nil
+}
+
+const summarizePrompt = "Summarize the preceding conversation as terse background context for continuing it. Reply with the summary only."
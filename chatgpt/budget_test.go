@@ -0,0 +1,82 @@
+package chatgpt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/otiai10/openaigo"
+)
+
+func TestUnitsOf(t *testing.T) {
+	conv := []Message{
+		System("system prompt"),
+		User("hi"),
+		{Role: "assistant", Content: "", ToolCalls: []openaigo.ToolCall{{ID: "call_1"}, {ID: "call_2"}}},
+		ToolResult("call_1", "f1", "a"),
+		ToolResult("call_2", "f2", "b"),
+		{Role: "assistant", Content: "done"},
+	}
+
+	units := unitsOf(conv)
+	if len(units) != 4 {
+		t.Fatalf("got %d units, want 4", len(units))
+	}
+	if !units[0].isSystem() {
+		t.Errorf("units[0] should be the system message")
+	}
+	if len(units[2]) != 3 {
+		t.Fatalf("units[2] (assistant tool_calls + its tool replies) has %d messages, want 3", len(units[2]))
+	}
+	if units[2][0].Role != "assistant" || units[2][1].Role != "tool" || units[2][2].Role != "tool" {
+		t.Errorf("units[2] roles = %v, want [assistant tool tool]", []string{units[2][0].Role, units[2][1].Role, units[2][2].Role})
+	}
+}
+
+func TestDropOldestKeepsSystemMessagesAndToolCallPairs(t *testing.T) {
+	conv := []Message{
+		System("system prompt"),
+		User("first turn"),
+		{Role: "assistant", Content: "", ToolCalls: []openaigo.ToolCall{{ID: "call_1"}}},
+		ToolResult("call_1", "f1", "result"),
+		User("latest turn"),
+	}
+
+	// overBy larger than the whole conversation's estimated size, so
+	// DropOldest drops every non-system unit: the system message must
+	// survive, and the tool_call/tool pair must go as a whole.
+	got, err := DropOldest(nil, &Client{Model: "gpt-4"}, conv, 1<<30)
+	if err != nil {
+		t.Fatalf("DropOldest: %v", err)
+	}
+
+	if got[0].Role != "system" {
+		t.Fatalf("system message was dropped: %+v", got)
+	}
+
+	// The tool_call/tool pair must never appear split: either both of
+	// conv[2] and conv[3] survive, or neither does.
+	hasCall := false
+	hasResult := false
+	for _, m := range got {
+		if m.Role == "assistant" && len(m.ToolCalls) > 0 {
+			hasCall = true
+		}
+		if m.Role == "tool" {
+			hasResult = true
+		}
+	}
+	if hasCall != hasResult {
+		t.Fatalf("tool_call/tool pair was split: hasCall=%v hasResult=%v, got=%+v", hasCall, hasResult, got)
+	}
+}
+
+func TestFlattenUnitsRoundTrip(t *testing.T) {
+	conv := []Message{
+		System("s"),
+		User("u"),
+	}
+	got := flattenUnits(unitsOf(conv))
+	if !reflect.DeepEqual(got, conv) {
+		t.Fatalf("flattenUnits(unitsOf(conv)) = %+v, want %+v", got, conv)
+	}
+}
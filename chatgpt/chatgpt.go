@@ -32,18 +32,19 @@ type Client struct {
 	// Defaults to 1.
 	N int `json:"n,omitempty"`
 
-	// TODO:
 	// Stream: If set, partial message deltas will be sent, like in ChatGPT.
 	// Tokens will be sent as data-only server-sent events as they become available,
 	// with the stream terminated by a data: [DONE] message.
-	// Stream bool `json:"stream,omitempty"`
+	// This is set to true automatically whenever StreamCallback is provided.
+	Stream bool `json:"stream,omitempty"`
 
-	// TODO:
-	// StreamCallback is a callback funciton to handle stream response.
-	// If provided, this library automatically set `Stream` `true`.
-	// This field is added by github.com/otiai10/openaigo only to handle Stream.
-	// Thus, it is omitted when the client excute HTTP request.
-	// StreamCallback func(res ChatCompletionResponse, done bool, err error) `json:"-"`
+	// StreamCallback is a callback function to handle stream response.
+	// If provided, this library automatically sets `Stream` `true` and dispatches
+	// one call per delta as it arrives, then one final call with done=true
+	// carrying the whole accumulated message.
+	// This field is added by this package only to handle Stream.
+	// Thus, it is omitted when the client executes the HTTP request.
+	StreamCallback func(delta Message, done bool, err error) `json:"-"`
 
 	// Stop: Up to 4 sequences where the API will stop generating further tokens.
 	// Defaults to null.
@@ -77,14 +78,109 @@ type Client struct {
 
 	// User: A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	// https://platform.openai.com/docs/guides/safety-best-practices/end-user-ids
+	// Ignored when RawUserID is set; set User directly only when callers
+	// already hand you an opaque id.
 	User string `json:"user,omitempty"`
 
+	// RawUserID is a caller-supplied end-user identifier (e.g. a Telegram or
+	// Discord ID) that has NOT been sent to OpenAI yet. When set, each
+	// Chat() call derives that request's `user` from it via UserIDHasher
+	// (or the default hasher, salted with UserIDSalt) so that raw PII
+	// never leaves this process. Chat() only ever reads RawUserID; it
+	// never writes User back, so setting RawUserID on one call can't leak
+	// that user's pseudonym into a later call for a different end-user.
+	RawUserID string `json:"-"`
+
+	// UserIDSalt salts the default UserIDHasher. Rotate it to invalidate
+	// every previously issued pseudonym.
+	UserIDSalt string `json:"-"`
+
+	// UserIDHasher overrides how RawUserID is turned into User. Defaults to
+	// DefaultUserIDHasher(c.UserIDSalt).
+	UserIDHasher UserIDHasher `json:"-"`
+
 	// Functions: A list of functions which GPT is allowed to request to call.
+	// Deprecated by OpenAI in favor of Tools. Kept for backward compatibility:
+	// when Tools is empty, it is translated into the equivalent Tools entries.
 	// Functions []Function `json:"functions,omitempty"`
 	Functions functioncall.Funcs `json:"functions,omitempty"`
 
 	// FunctionCall: You ain't need it. Default is "auto".
+	// Deprecated by OpenAI in favor of ToolChoice.
 	FunctionCall string `json:"function_call,omitempty"`
+
+	// Tools: A list of tools GPT is allowed to request to call.
+	// This supersedes Functions; a single assistant turn may request
+	// several tool calls at once, each dispatched in parallel and each
+	// answered with its own role:"tool" message carrying the matching ID.
+	Tools []openaigo.Tool `json:"tools,omitempty"`
+
+	// ToolChoice controls which (if any) tool is called by the model:
+	// "none", "auto", "required", or a specific
+	// {"type":"function","function":{"name":...}} selector.
+	ToolChoice interface{} `json:"tool_choice,omitempty"`
+
+	// ResponseFormat constrains the format of the assistant's message:
+	// {"type":"text"}, {"type":"json_object"}, or
+	// {"type":"json_schema","json_schema":{name,schema,strict}}.
+	// Use SchemaOf to build the json_schema variant from a Go struct.
+	ResponseFormat *openaigo.ResponseFormat `json:"response_format,omitempty"`
+
+	// ResponseFormatRetries: how many times ChatInto re-asks the model with
+	// an auto-generated correction message after the assistant's Content
+	// fails to unmarshal into the caller's target. Defaults to 0 (no retry).
+	ResponseFormatRetries int `json:"-"`
+
+	// Seed: If specified, the system will make a best effort to sample
+	// deterministically, such that repeated requests with the same seed and
+	// parameters return the same result. Determinism is not guaranteed;
+	// compare the fingerprint Chat returns across calls to detect backend
+	// changes that can affect it.
+	Seed *int `json:"seed,omitempty"`
+
+	// Logprobs: Whether to return log probabilities of the output tokens.
+	Logprobs *bool `json:"logprobs,omitempty"`
+
+	// TopLogprobs: Number of most likely tokens to return at each token
+	// position, each with an associated log probability. Logprobs must be
+	// true if this is used.
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
+
+	// MaxCompletionTokens: The maximum number of tokens allowed for the
+	// generated answer, for o1-series reasoning models. These models are
+	// served under `max_completion_tokens` instead of `max_tokens`; see
+	// StrictO1.
+	MaxCompletionTokens int `json:"-"`
+
+	// StrictO1 rejects, client-side, the request fields that o1-series
+	// reasoning models don't support (see ErrO1BetaLimitation), instead of
+	// letting OpenAI reject them server-side. Defaults to true; set to
+	// false once OpenAI lifts a restriction this version doesn't know
+	// about yet. Only takes effect when Model matches the o1 family.
+	StrictO1 bool `json:"-"`
+
+	// History persists conv across process restarts. Chat() itself never
+	// reads or writes it; callers Load before the first turn and Save
+	// after each one, keyed by an application-supplied conversation id.
+	History Store `json:"-"`
+
+	// TokenBudget caps the combined size of the conversation plus
+	// MaxTokens (MaxCompletionTokens on o1-series models). When Chat() estimates the total would exceed it, the
+	// oldest non-system messages are truncated first via TruncatePolicy.
+	// Zero disables budget enforcement.
+	TokenBudget int `json:"-"`
+
+	// Summarize, when true and TruncatePolicy is unset, uses
+	// SummarizeOldest instead of DropOldest: messages that would otherwise
+	// be discarded are replaced with a single synthetic system message
+	// (a user message on o1-series models) produced by asking the model to
+	// summarize them.
+	Summarize bool `json:"-"`
+
+	// TruncatePolicy overrides how Chat() shrinks a conversation that
+	// would exceed TokenBudget. Defaults to DropOldest, or SummarizeOldest
+	// when Summarize is set.
+	TruncatePolicy TruncatePolicy `json:"-"`
 }
 
 type Message openaigo.Message
@@ -94,11 +190,29 @@ func New(apikey, model string) *Client {
 		Client: openaigo.Client{
 			APIKey: apikey,
 		},
-		Model: model,
+		Model:    model,
+		StrictO1: true,
 	}
 }
 
-func (c *Client) Chat(ctx context.Context, conv []Message) ([]Message, error) {
+// buildRequest validates conv against StrictO1 (if applicable), truncates it
+// to TokenBudget, and assembles the openaigo.ChatRequest for it, applying
+// every *Client option: Tools/Functions translation, o1's
+// MaxCompletionTokens vs MaxTokens split, and RawUserID hashing. Both Chat
+// and ChatWithLogprobs build their request through here so that option
+// handling can't drift between them.
+func (c *Client) buildRequest(ctx context.Context, conv []Message) (openaigo.ChatRequest, []Message, error) {
+	if c.StrictO1 && isO1Model(c.Model) {
+		if err := c.validateO1(conv); err != nil {
+			return openaigo.ChatRequest{}, conv, err
+		}
+	}
+
+	conv, err := c.truncateToBudget(ctx, conv)
+	if err != nil {
+		return openaigo.ChatRequest{}, conv, err
+	}
+
 	// Create messages from conv
 	messages := make([]openaigo.Message, len(conv))
 	for i, m := range conv {
@@ -106,25 +220,89 @@ func (c *Client) Chat(ctx context.Context, conv []Message) ([]Message, error) {
 	}
 	// Create request
 	req := openaigo.ChatRequest{
-		Model:     c.Model,
-		Messages:  messages,
-		Functions: functioncall.Funcs(c.Functions),
+		Model:          c.Model,
+		Messages:       messages,
+		Tools:          c.Tools,
+		ToolChoice:     c.ToolChoice,
+		ResponseFormat: c.ResponseFormat,
+		Seed:           c.Seed,
+		Logprobs:       c.Logprobs,
+		TopLogprobs:    c.TopLogprobs,
+		Stream:         c.Stream || c.StreamCallback != nil,
 		// TODO: more options from from *Client
 	}
+	// Functions/FunctionCall are deprecated by OpenAI in favor of
+	// Tools/ToolChoice. They are translated into the equivalent Tools
+	// entries rather than also being sent as `functions`, which would
+	// describe the same calls twice with undefined precedence; an
+	// explicit c.Tools always wins over c.Functions.
+	if len(req.Tools) == 0 {
+		req.Tools = toolsFromFunctions(c.Functions)
+		if req.ToolChoice == nil && c.FunctionCall != "" {
+			switch c.FunctionCall {
+			case "none", "auto":
+				req.ToolChoice = c.FunctionCall
+			default:
+				req.ToolChoice = map[string]interface{}{
+					"type":     "function",
+					"function": map[string]string{"name": c.FunctionCall},
+				}
+			}
+		}
+	}
+	if isO1Model(c.Model) {
+		req.MaxCompletionTokens = c.MaxCompletionTokens
+	} else {
+		req.MaxTokens = c.MaxTokens
+	}
+	req.User = c.User
+	if c.RawUserID != "" {
+		hasher := c.UserIDHasher
+		if hasher == nil {
+			hasher = DefaultUserIDHasher(c.UserIDSalt)
+		}
+		req.User = hasher(c.RawUserID)
+	}
+
+	return req, conv, nil
+}
+
+// Chat sends conv to the model and appends its reply (and, if the model
+// asked to call a function or tool, the dispatched results and the
+// follow-up reply) to it. The second return value is the system_fingerprint
+// of the most recent response in that exchange; unlike MaxTokens or other
+// *Client options, it describes a single run, not this Client's
+// configuration, so it's returned per-call rather than stored on c, where
+// concurrent calls sharing c would race on it.
+func (c *Client) Chat(ctx context.Context, conv []Message) ([]Message, string, error) {
+	req, conv, err := c.buildRequest(ctx, conv)
+	if err != nil {
+		return conv, "", err
+	}
+
+	if req.Stream {
+		return c.chatStream(ctx, req, conv)
+	}
+
 	// Call API
 	res, err := c.Client.Chat(ctx, req)
 	if err != nil {
-		return conv, err
+		return conv, "", err
 	}
+	fingerprint := res.SystemFingerprint
 	conv = append(conv, Message(res.Choices[0].Message))
 
+	if len(res.Choices[0].Message.ToolCalls) > 0 {
+		return c.chatToolCalls(ctx, res.Choices[0].Message.ToolCalls, conv)
+	}
+
 	if res.Choices[0].Message.FunctionCall != nil {
 		call := res.Choices[0].Message.FunctionCall
 		conv = append(conv, Func(call.Name(), c.Functions.Call(call)))
 		return c.Chat(ctx, conv)
 	}
 
-	return conv, nil
+	return conv, fingerprint, nil
 }
 
 func User(message string) Message {
@@ -142,6 +320,17 @@ func Func(name string, data interface{}) Message {
 	}
 }
 
+// ToolResult builds the role:"tool" message that answers a single ToolCall,
+// carrying the ToolCallID the model used to issue it.
+func ToolResult(toolCallID, name string, data interface{}) Message {
+	return Message{
+		Role:       "tool",
+		ToolCallID: toolCallID,
+		Name:       name,
+		Content:    fmt.Sprintf("%+v\n", data),
+	}
+}
+
 func System(message string) Message {
 	return Message{
 		Role:    "system",
@@ -0,0 +1,101 @@
+package chatgpt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persists and reloads a conversation across process restarts, keyed
+// by an application-supplied conversation id (e.g. a chat room or user id).
+// Chat() never touches a Store itself; callers decide when to Load before
+// the first turn and Save after each one.
+type Store interface {
+	Load(key string) ([]Message, error)
+	Save(key string, conv []Message) error
+}
+
+// MemoryStore is an in-memory Store. Conversations don't survive process
+// restarts; it exists mainly for tests and for callers who only want the
+// TokenBudget truncation behavior without real persistence.
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string][]Message
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string][]Message{}}
+}
+
+func (s *MemoryStore) Load(key string) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.data[key]...), nil
+}
+
+func (s *MemoryStore) Save(key string, conv []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]Message(nil), conv...)
+	return nil
+}
+
+// FileStore persists each conversation as one JSON file named <key>.json
+// under Dir.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a FileStore rooted at dir. dir is created lazily on
+// the first Save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (s *FileStore) Load(key string) ([]Message, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var conv []Message
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("chatgpt: decoding history for %q: %w", key, err)
+	}
+	return conv, nil
+}
+
+func (s *FileStore) Save(key string, conv []Message) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(conv)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// path resolves key to a file under Dir, rejecting any key that isn't a
+// single path element: a key is an opaque application-supplied id, never a
+// path, so "../", absolute paths, and separators must not be allowed to
+// escape Dir.
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." {
+		return "", fmt.Errorf("chatgpt: invalid history key %q", key)
+	}
+	return filepath.Join(s.Dir, key+".json"), nil
+}
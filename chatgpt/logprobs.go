@@ -0,0 +1,72 @@
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+)
+
+// TokenLogprob is the log probability of a single output token, together
+// with the log probabilities of the TopLogprobs most likely alternatives at
+// that same position. It mirrors the shape of
+// openaigo.ChatResponseChoice.Logprobs.Content[i].
+type TokenLogprob struct {
+	Token       string               `json:"token"`
+	Logprob     float64              `json:"logprob"`
+	TopLogprobs []TokenLogprobOption `json:"top_logprobs,omitempty"`
+}
+
+// TokenLogprobOption is one alternative token considered at a given
+// position, as requested via Client.TopLogprobs.
+type TokenLogprobOption struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+}
+
+// ChatWithLogprobs behaves like Chat, but forces Logprobs on for this call
+// and additionally returns the per-token log probabilities of the
+// assistant's message, so callers can build confidence-scoring or
+// constrained-decoding tooling on top without permanently flipping
+// c.Logprobs for every future call. It builds its request the same way
+// Chat does, so MaxTokens/MaxCompletionTokens, RawUserID hashing,
+// ResponseFormat, and o1 validation all still apply. Like Chat, its last
+// return value before the error is the system_fingerprint of this specific
+// response; c is never mutated, so concurrent calls on the same Client
+// can't race or stomp each other's Logprobs override or fingerprint.
+// Unlike Chat, it makes exactly one non-streamed request: StreamCallback is
+// not called, and function or tool calls the model asks for are appended
+// but not dispatched.
+func (c *Client) ChatWithLogprobs(ctx context.Context, conv []Message) ([]Message, []TokenLogprob, string, error) {
+	req, conv, err := c.buildRequest(ctx, conv)
+	if err != nil {
+		return conv, nil, "", err
+	}
+	// buildRequest's validateO1 only sees c.Logprobs, which this call leaves
+	// untouched; check the o1/logprobs incompatibility it would otherwise
+	// miss for the Logprobs this call is about to force on, without
+	// mutating c to make it visible there.
+	if c.StrictO1 && isO1Model(c.Model) {
+		return conv, nil, "", fmt.Errorf("chatgpt: o1-series models don't support logprobs: %w", &ErrO1BetaLimitation{Field: "logprobs"})
+	}
+	enabled := true
+	req.Logprobs = &enabled
+	req.Stream = false
+
+	res, err := c.Client.Chat(ctx, req)
+	if err != nil {
+		return conv, nil, "", err
+	}
+	conv = append(conv, Message(res.Choices[0].Message))
+
+	logprobs := make([]TokenLogprob, 0)
+	if res.Choices[0].Logprobs != nil {
+		for _, tok := range res.Choices[0].Logprobs.Content {
+			tl := TokenLogprob{Token: tok.Token, Logprob: tok.Logprob}
+			for _, alt := range tok.TopLogprobs {
+				tl.TopLogprobs = append(tl.TopLogprobs, TokenLogprobOption{Token: alt.Token, Logprob: alt.Logprob})
+			}
+			logprobs = append(logprobs, tl)
+		}
+	}
+
+	return conv, logprobs, res.SystemFingerprint, nil
+}
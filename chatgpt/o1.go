@@ -0,0 +1,62 @@
+package chatgpt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrO1BetaLimitation reports a request field that the o1-series reasoning
+// models don't support yet, caught client-side by StrictO1 before OpenAI
+// would otherwise reject the request server-side.
+type ErrO1BetaLimitation struct {
+	Field string
+}
+
+func (e *ErrO1BetaLimitation) Error() string {
+	return fmt.Sprintf("chatgpt: field %q is not supported by o1-series models; see StrictO1", e.Field)
+}
+
+// isO1Model reports whether model belongs to the o1 reasoning family, e.g.
+// "o1-preview", "o1-preview-2024-09-12", "o1-mini".
+func isO1Model(model string) bool {
+	return strings.HasPrefix(model, "o1-") || model == "o1"
+}
+
+// validateO1 rejects, client-side, the fields and message roles that
+// o1-series models reject server-side: sampling controls, logprobs,
+// tools/functions, streaming, and system messages.
+func (c *Client) validateO1(conv []Message) error {
+	if c.MaxTokens != 0 {
+		return fmt.Errorf("chatgpt: o1-series models use MaxCompletionTokens, not MaxTokens: %w", &ErrO1BetaLimitation{Field: "max_tokens"})
+	}
+	switch {
+	case c.Temperature != 0 && c.Temperature != 1:
+		return &ErrO1BetaLimitation{Field: "temperature"}
+	case c.TopP != 0 && c.TopP != 1:
+		return &ErrO1BetaLimitation{Field: "top_p"}
+	case c.N > 1:
+		return &ErrO1BetaLimitation{Field: "n"}
+	case c.PresencePenalty != 0:
+		return &ErrO1BetaLimitation{Field: "presence_penalty"}
+	case c.FrequencyPenalty != 0:
+		return &ErrO1BetaLimitation{Field: "frequency_penalty"}
+	case c.LogitBias != nil:
+		return &ErrO1BetaLimitation{Field: "logit_bias"}
+	case c.Logprobs != nil:
+		return &ErrO1BetaLimitation{Field: "logprobs"}
+	case c.TopLogprobs != nil:
+		return &ErrO1BetaLimitation{Field: "top_logprobs"}
+	case len(c.Tools) > 0 || len(c.Functions) > 0:
+		return &ErrO1BetaLimitation{Field: "tools"}
+	case c.Stream || c.StreamCallback != nil:
+		return &ErrO1BetaLimitation{Field: "stream"}
+	}
+
+	for _, m := range conv {
+		if m.Role == "system" {
+			return fmt.Errorf("chatgpt: o1-series models don't support role %q, use \"user\" instead: %w", "system", &ErrO1BetaLimitation{Field: "messages[].role"})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/otiai10/openaigo"
+	"github.com/otiai10/openaigo/functioncall"
+)
+
+// SchemaOf reflects a Go struct into an openaigo.ResponseFormat of type
+// "json_schema", reusing the same reflection functioncall uses to build
+// parameter schemas for legacy Functions. The schema name is taken from
+// v's type name, and strict mode is enabled, matching OpenAI's own
+// recommendation for json_schema responses.
+func SchemaOf(v interface{}) *openaigo.ResponseFormat {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := "Schema"
+	if t != nil && t.Name() != "" {
+		name = t.Name()
+	}
+	return &openaigo.ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &openaigo.JSONSchema{
+			Name:   name,
+			Schema: functioncall.SchemaOf(v),
+			Strict: true,
+		},
+	}
+}
+
+// ChatInto behaves like Chat, but additionally unmarshals the assistant's
+// final Content into out. It is meant to be paired with ResponseFormat set
+// to a JSON-object or json_schema format. When unmarshaling fails, it
+// re-asks the model up to c.ResponseFormatRetries times with an
+// auto-generated correction message describing the error before giving up.
+func (c *Client) ChatInto(ctx context.Context, conv []Message, out interface{}) ([]Message, error) {
+	for attempt := 0; ; attempt++ {
+		var err error
+		conv, _, err = c.Chat(ctx, conv)
+		if err != nil {
+			return conv, err
+		}
+
+		last := conv[len(conv)-1]
+		jsonErr := json.Unmarshal([]byte(last.Content), out)
+		if jsonErr == nil {
+			return conv, nil
+		}
+		if attempt >= c.ResponseFormatRetries {
+			return conv, fmt.Errorf("chatgpt: response did not match the requested schema: %w", jsonErr)
+		}
+		conv = append(conv, User(fmt.Sprintf(
+			"Your previous response could not be parsed as valid JSON matching the requested schema: %v. Please resend the full response as corrected JSON only.",
+			jsonErr,
+		)))
+	}
+}
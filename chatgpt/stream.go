@@ -0,0 +1,292 @@
+package chatgpt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/otiai10/openaigo"
+)
+
+// defaultChatCompletionsURL is the fallback used to open the raw SSE stream
+// when c.Client.BaseURL is unset. openaigo.Client.Chat always performs a
+// single, fully-buffered request, so streaming is handled here instead, but
+// it still goes through c.Client's own configuration (base URL, HTTP
+// client, organization) rather than hardcoding a connection of its own.
+const defaultChatCompletionsURL = "https://api.openai.com/v1/chat/completions"
+
+// doneSentinel is the terminating frame OpenAI sends to end a stream.
+const doneSentinel = "[DONE]"
+
+// ChatCompletionChunk is a single `data: {...}` frame of a streamed
+// chat completion, as sent when `stream: true` is set on the request.
+type ChatCompletionChunk struct {
+	ID                string        `json:"id"`
+	Object            string        `json:"object"`
+	Created           int64         `json:"created"`
+	Model             string        `json:"model"`
+	SystemFingerprint string        `json:"system_fingerprint"`
+	Choices           []ChunkChoice `json:"choices"`
+}
+
+// ChunkChoice is one of the `choices` of a ChatCompletionChunk.
+type ChunkChoice struct {
+	Index        int        `json:"index"`
+	Delta        ChunkDelta `json:"delta"`
+	FinishReason string     `json:"finish_reason"`
+}
+
+// ChunkDelta carries the incremental fields of a streamed message.
+// Role is only present on the first chunk of a choice, Content is appended
+// token by token, FunctionCall is assembled the same way (Name arrives
+// once, Arguments arrives in fragments), and ToolCalls arrives as one
+// fragment per parallel tool call, matched up by ChunkToolCall.Index.
+type ChunkDelta struct {
+	Role         string             `json:"role,omitempty"`
+	Content      string             `json:"content,omitempty"`
+	FunctionCall *ChunkFunctionCall `json:"function_call,omitempty"`
+	ToolCalls    []ChunkToolCall    `json:"tool_calls,omitempty"`
+}
+
+// ChunkFunctionCall is the streamed counterpart of openaigo.FunctionCall:
+// its fields arrive piecemeal across several chunks and must be
+// concatenated by the caller.
+type ChunkFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ChunkToolCall is one fragment of one parallel tool call. Index identifies
+// which tool call this fragment belongs to; ID and Function.Name only
+// arrive on that call's first fragment, while Function.Arguments arrives in
+// further fragments that must be concatenated in order.
+type ChunkToolCall struct {
+	Index    int                `json:"index"`
+	ID       string             `json:"id,omitempty"`
+	Type     string             `json:"type,omitempty"`
+	Function *ChunkFunctionCall `json:"function,omitempty"`
+}
+
+// streamURL returns the chat completions endpoint to open the SSE stream
+// against, honoring c.Client.BaseURL when set so a streamed call reaches
+// the same backend (proxy, Azure deployment, mock server, ...) a
+// non-streamed call would.
+func (c *Client) streamURL() string {
+	if c.Client.BaseURL == "" {
+		return defaultChatCompletionsURL
+	}
+	return strings.TrimRight(c.Client.BaseURL, "/") + "/chat/completions"
+}
+
+// streamHTTPClient returns the *http.Client a streamed call should use,
+// honoring c.Client.HTTPClient so timeouts, proxies, and transports
+// configured there also apply to streaming.
+func (c *Client) streamHTTPClient() *http.Client {
+	if c.Client.HTTPClient != nil {
+		return c.Client.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// chatStream opens req as a Server-Sent Events stream, feeds every delta to
+// c.StreamCallback as it arrives, and accumulates them into the final
+// Message that Chat() would otherwise have received from a non-streamed
+// response. It preserves the existing full-auto function_call and
+// tool_calls dispatch: once the stream ends, a function call is run via
+// c.Functions.Call and tool calls are dispatched the same way Chat()'s
+// non-streamed path does, in parallel, before recursing. Its second return
+// value is the system_fingerprint of the most recent chunk, the same
+// per-call value Chat's non-streamed path returns.
+func (c *Client) chatStream(ctx context.Context, req openaigo.ChatRequest, conv []Message) ([]Message, string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return conv, "", err
+	}
+
+	httpreq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.streamURL(), bytes.NewReader(body))
+	if err != nil {
+		return conv, "", err
+	}
+	httpreq.Header.Set("Content-Type", "application/json")
+	httpreq.Header.Set("Authorization", "Bearer "+c.APIKey)
+	httpreq.Header.Set("Accept", "text/event-stream")
+	if c.Client.OrgID != "" {
+		httpreq.Header.Set("OpenAI-Organization", c.Client.OrgID)
+	}
+
+	res, err := c.streamHTTPClient().Do(httpreq)
+	if err != nil {
+		return conv, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return conv, "", fmt.Errorf("chatgpt: stream request failed with status %s", res.Status)
+	}
+
+	var role, content, funcName, funcArgs, finishReason, fingerprint string
+	toolCalls := map[int]*ChunkToolCall{}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == doneSentinel {
+			break
+		}
+		if data == "" {
+			continue
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			if c.StreamCallback != nil {
+				c.StreamCallback(Message{}, false, err)
+			}
+			return conv, "", err
+		}
+		if chunk.SystemFingerprint != "" {
+			fingerprint = chunk.SystemFingerprint
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		choice := chunk.Choices[0]
+		delta := choice.Delta
+
+		if delta.Role != "" {
+			role = delta.Role
+		}
+		if delta.Content != "" {
+			content += delta.Content
+		}
+		if delta.FunctionCall != nil {
+			funcName += delta.FunctionCall.Name
+			funcArgs += delta.FunctionCall.Arguments
+		}
+		for _, tc := range delta.ToolCalls {
+			acc, ok := toolCalls[tc.Index]
+			if !ok {
+				acc = &ChunkToolCall{Index: tc.Index, Function: &ChunkFunctionCall{}}
+				toolCalls[tc.Index] = acc
+			}
+			if tc.ID != "" {
+				acc.ID = tc.ID
+			}
+			if tc.Type != "" {
+				acc.Type = tc.Type
+			}
+			if tc.Function != nil {
+				acc.Function.Name += tc.Function.Name
+				acc.Function.Arguments += tc.Function.Arguments
+			}
+		}
+		if choice.FinishReason != "" {
+			finishReason = choice.FinishReason
+		}
+
+		if c.StreamCallback != nil {
+			c.StreamCallback(Message{Role: delta.Role, Content: delta.Content}, false, nil)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return conv, "", err
+	}
+
+	if role == "" {
+		role = "assistant"
+	}
+	msg := Message{Role: role, Content: content}
+	if funcName != "" {
+		// Re-assemble the name/arguments fragments into the same JSON shape
+		// a non-streamed response would have delivered, and let openaigo
+		// decode it the same way it always does.
+		raw, err := json.Marshal(struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		}{funcName, funcArgs})
+		if err != nil {
+			return conv, "", err
+		}
+		var fc openaigo.FunctionCall
+		if err := json.Unmarshal(raw, &fc); err != nil {
+			return conv, "", err
+		}
+		msg.FunctionCall = &fc
+	}
+
+	var calls []openaigo.ToolCall
+	if len(toolCalls) > 0 {
+		calls, err = assembleToolCalls(toolCalls)
+		if err != nil {
+			return conv, "", err
+		}
+		msg.ToolCalls = calls
+	}
+
+	if c.StreamCallback != nil {
+		c.StreamCallback(msg, true, nil)
+	}
+
+	conv = append(conv, msg)
+
+	if finishReason == "tool_calls" && len(calls) > 0 {
+		return c.chatToolCalls(ctx, calls, conv)
+	}
+
+	if finishReason == "function_call" && msg.FunctionCall != nil {
+		call := msg.FunctionCall
+		conv = append(conv, Func(call.Name(), c.Functions.Call(call)))
+		return c.Chat(ctx, conv)
+	}
+
+	return conv, fingerprint, nil
+}
+
+// assembleToolCalls reassembles the accumulated per-index tool-call
+// fragments into the same []openaigo.ToolCall shape a non-streamed
+// response would have delivered, by index order, via the same
+// marshal/unmarshal round trip used for the legacy FunctionCall accumulator.
+func assembleToolCalls(acc map[int]*ChunkToolCall) ([]openaigo.ToolCall, error) {
+	indices := make([]int, 0, len(acc))
+	for i := range acc {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	type toolCallJSON struct {
+		ID       string `json:"id"`
+		Type     string `json:"type"`
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	}
+
+	raws := make([]toolCallJSON, len(indices))
+	for i, idx := range indices {
+		tc := acc[idx]
+		raws[i].ID = tc.ID
+		raws[i].Type = tc.Type
+		raws[i].Function.Name = tc.Function.Name
+		raws[i].Function.Arguments = tc.Function.Arguments
+	}
+
+	data, err := json.Marshal(raws)
+	if err != nil {
+		return nil, err
+	}
+	var calls []openaigo.ToolCall
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
@@ -0,0 +1,59 @@
+package chatgpt
+
+import "testing"
+
+func TestAssembleToolCalls(t *testing.T) {
+	cases := []struct {
+		name string
+		acc  map[int]*ChunkToolCall
+		want []struct {
+			id, typ, fname, fargs string
+		}
+	}{
+		{
+			name: "single call",
+			acc: map[int]*ChunkToolCall{
+				0: {Index: 0, ID: "call_1", Type: "function", Function: &ChunkFunctionCall{Name: "get_weather", Arguments: `{"city":"ny"}`}},
+			},
+			want: []struct{ id, typ, fname, fargs string }{
+				{"call_1", "function", "get_weather", `{"city":"ny"}`},
+			},
+		},
+		{
+			name: "parallel calls reassembled in index order regardless of map iteration order",
+			acc: map[int]*ChunkToolCall{
+				2: {Index: 2, ID: "call_3", Type: "function", Function: &ChunkFunctionCall{Name: "c", Arguments: "3"}},
+				0: {Index: 0, ID: "call_1", Type: "function", Function: &ChunkFunctionCall{Name: "a", Arguments: "1"}},
+				1: {Index: 1, ID: "call_2", Type: "function", Function: &ChunkFunctionCall{Name: "b", Arguments: "2"}},
+			},
+			want: []struct{ id, typ, fname, fargs string }{
+				{"call_1", "function", "a", "1"},
+				{"call_2", "function", "b", "2"},
+				{"call_3", "function", "c", "3"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls, err := assembleToolCalls(tc.acc)
+			if err != nil {
+				t.Fatalf("assembleToolCalls: %v", err)
+			}
+			if len(calls) != len(tc.want) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tc.want))
+			}
+			for i, want := range tc.want {
+				if calls[i].ID != want.id {
+					t.Errorf("calls[%d].ID = %q, want %q", i, calls[i].ID, want.id)
+				}
+				if calls[i].Type != want.typ {
+					t.Errorf("calls[%d].Type = %q, want %q", i, calls[i].Type, want.typ)
+				}
+				if calls[i].Function.Name() != want.fname {
+					t.Errorf("calls[%d].Function.Name() = %q, want %q", i, calls[i].Function.Name(), want.fname)
+				}
+			}
+		})
+	}
+}
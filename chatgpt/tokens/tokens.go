@@ -0,0 +1,171 @@
+// Package tokens estimates chat-completion token usage ahead of a request,
+// so callers can budget a conversation against a model's context window
+// without waiting on a round trip.
+package tokens
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Encoding identifies which of OpenAI's BPE vocabularies a model is served
+// under. Counting against the right one keeps the estimate close; the
+// wrong one can be off by a large margin on non-Latin text.
+type Encoding string
+
+const (
+	// CL100KBase is used by gpt-3.5-turbo, gpt-4, and gpt-4-turbo.
+	CL100KBase Encoding = "cl100k_base"
+	// O200KBase is used by gpt-4o and the o1 reasoning family.
+	O200KBase Encoding = "o200k_base"
+)
+
+// EncodingForModel returns the Encoding the given model is served under.
+func EncodingForModel(model string) Encoding {
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "o1"):
+		return O200KBase
+	default:
+		return CL100KBase
+	}
+}
+
+// Encoder estimates the number of tokens a string would consume.
+//
+// This is a dependency-free approximation, not a port of tiktoken: the real
+// cl100k_base/o200k_base merge tables are several hundred thousand entries
+// and aren't vendored here. Instead of a single bytes-per-token ratio, it
+// pre-splits text along the same rough word/number/punctuation/whitespace
+// boundaries tiktoken's regex pre-tokenizer uses and charges each run a
+// density appropriate to its class and Encoding: prose-like letter runs
+// average OpenAI's own published ~4 characters per token under cl100k_base,
+// digit runs ~3 (both vocabularies chunk digits into runs of up to three),
+// punctuation/symbol runs close to one token per character (code and JSON
+// are symbol-dense and tokenize far denser than prose), and any non-ASCII
+// rune close to one token per rune under cl100k_base (its Latin-heavy
+// training data rarely merges other scripts into multi-rune tokens).
+// o200k_base's larger, newer vocabulary compresses both letter runs and
+// non-ASCII scripts noticeably better, which is the whole reason gpt-4o and
+// the o1 family were moved onto it; its per-class densities below are
+// scaled accordingly. That tracks the real encoders closely enough to drive
+// TokenBudget truncation decisions, but it must not be relied on where an
+// exact count is required.
+type Encoder struct {
+	Encoding Encoding
+}
+
+// NewEncoder returns an Encoder for the given Encoding.
+func NewEncoder(encoding Encoding) *Encoder {
+	return &Encoder{Encoding: encoding}
+}
+
+// Count estimates the number of tokens text would encode to.
+func (e *Encoder) Count(text string) int {
+	total := 0
+	for _, p := range splitIntoPieces(text) {
+		total += p.count(e.Encoding)
+	}
+	return total
+}
+
+// pieceClass is the character class a run of a piece belongs to.
+type pieceClass int
+
+const (
+	classWhitespace pieceClass = iota
+	classLetter
+	classDigit
+	classOther
+)
+
+// piece is one run of same-class, same-ASCII-ness runes.
+type piece struct {
+	text  string
+	class pieceClass
+	ascii bool
+}
+
+// count estimates the number of tokens p would encode to, per the class-,
+// script-, and Encoding-specific densities documented on Encoder.
+func (p piece) count(encoding Encoding) int {
+	if p.text == "" {
+		return 0
+	}
+	if !p.ascii {
+		if encoding == O200KBase {
+			// o200k_base's larger vocabulary merges non-Latin scripts
+			// roughly twice as densely as cl100k_base's.
+			return ceilDiv(utf8.RuneCountInString(p.text), 2)
+		}
+		return utf8.RuneCountInString(p.text)
+	}
+	switch p.class {
+	case classDigit:
+		return ceilDiv(len(p.text), 3)
+	case classOther:
+		return len(p.text)
+	default: // classLetter, classWhitespace
+		charsPerToken := 4
+		if encoding == O200KBase {
+			charsPerToken = 5
+		}
+		return ceilDiv(len(p.text), charsPerToken)
+	}
+}
+
+func ceilDiv(n, d int) int {
+	if n == 0 {
+		return 0
+	}
+	q := (n + d - 1) / d
+	if q == 0 {
+		q = 1
+	}
+	return q
+}
+
+// splitIntoPieces breaks text on the same boundaries tiktoken's
+// pre-tokenizer regex treats as token-start candidates: runs of letters,
+// runs of digits, runs of punctuation/symbols, and runs of whitespace are
+// each their own piece, further split wherever ASCII-ness changes so
+// non-Latin scripts are never priced at the ASCII density.
+func splitIntoPieces(text string) []piece {
+	var pieces []piece
+	var current strings.Builder
+	var currentClass pieceClass
+	var currentASCII bool
+
+	classOf := func(r rune) pieceClass {
+		switch {
+		case unicode.IsSpace(r):
+			return classWhitespace
+		case unicode.IsLetter(r):
+			return classLetter
+		case unicode.IsDigit(r):
+			return classDigit
+		default:
+			return classOther
+		}
+	}
+
+	flush := func() {
+		if current.Len() > 0 {
+			pieces = append(pieces, piece{text: current.String(), class: currentClass, ascii: currentASCII})
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		class := classOf(r)
+		ascii := r <= unicode.MaxASCII
+		if current.Len() > 0 && (class != currentClass || ascii != currentASCII) {
+			flush()
+		}
+		currentClass, currentASCII = class, ascii
+		current.WriteRune(r)
+	}
+	flush()
+
+	return pieces
+}
@@ -0,0 +1,63 @@
+package tokens
+
+import "testing"
+
+func TestEncodingForModel(t *testing.T) {
+	cases := map[string]Encoding{
+		"gpt-3.5-turbo": CL100KBase,
+		"gpt-4":         CL100KBase,
+		"gpt-4-turbo":   CL100KBase,
+		"gpt-4o":        O200KBase,
+		"gpt-4o-mini":   O200KBase,
+		"o1-preview":    O200KBase,
+		"o1-mini":       O200KBase,
+	}
+	for model, want := range cases {
+		if got := EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestCountDiffersByClass(t *testing.T) {
+	enc := NewEncoder(CL100KBase)
+
+	letters := enc.Count("abcdefgh") // 8 ASCII letters
+	digits := enc.Count("12345678")  // 8 ASCII digits
+	symbols := enc.Count("!@#$%^&*") // 8 ASCII symbols
+
+	if !(symbols > digits && digits > letters) {
+		t.Fatalf("expected symbols(%d) > digits(%d) > letters(%d)", symbols, digits, letters)
+	}
+}
+
+func TestCountO200KBaseIsDenserThanCL100KBase(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog and keeps running"
+
+	cl100k := NewEncoder(CL100KBase).Count(text)
+	o200k := NewEncoder(O200KBase).Count(text)
+
+	if o200k >= cl100k {
+		t.Fatalf("o200k_base count (%d) should be lower than cl100k_base's (%d) for the same prose", o200k, cl100k)
+	}
+}
+
+func TestCountNonASCIIDiffersByEncoding(t *testing.T) {
+	text := "こんにちは世界" // 7 runes, no ASCII
+
+	cl100k := NewEncoder(CL100KBase).Count(text)
+	o200k := NewEncoder(O200KBase).Count(text)
+
+	if cl100k != 7 {
+		t.Errorf("cl100k_base count of 7 non-ASCII runes = %d, want 7 (~1 token/rune)", cl100k)
+	}
+	if o200k >= cl100k {
+		t.Errorf("o200k_base count (%d) should be lower than cl100k_base's (%d) for the same non-Latin text", o200k, cl100k)
+	}
+}
+
+func TestCountEmpty(t *testing.T) {
+	if got := NewEncoder(CL100KBase).Count(""); got != 0 {
+		t.Errorf("Count(\"\") = %d, want 0", got)
+	}
+}
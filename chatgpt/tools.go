@@ -0,0 +1,48 @@
+package chatgpt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/otiai10/openaigo"
+	"github.com/otiai10/openaigo/functioncall"
+)
+
+// toolsFromFunctions translates the legacy Functions field into the
+// equivalent []openaigo.Tool entries, so callers who haven't migrated to
+// Tools yet still get dispatched through the same tool_calls code path.
+func toolsFromFunctions(funcs functioncall.Funcs) []openaigo.Tool {
+	if len(funcs) == 0 {
+		return nil
+	}
+	tools := make([]openaigo.Tool, len(funcs))
+	for i, f := range funcs {
+		tools[i] = openaigo.Tool{
+			Type:     "function",
+			Function: f,
+		}
+	}
+	return tools
+}
+
+// chatToolCalls dispatches every ToolCall of the assistant's last turn to
+// c.Functions.Call in parallel, appends one role:"tool" message per call
+// carrying the matching ToolCallID, and recurses through Chat so the model
+// can see all the results at once.
+func (c *Client) chatToolCalls(ctx context.Context, calls []openaigo.ToolCall, conv []Message) ([]Message, string, error) {
+	results := make([]Message, len(calls))
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call openaigo.ToolCall) {
+			defer wg.Done()
+			data := c.Functions.Call(call.Function)
+			results[i] = ToolResult(call.ID, call.Function.Name(), data)
+		}(i, call)
+	}
+	wg.Wait()
+
+	conv = append(conv, results...)
+	return c.Chat(ctx, conv)
+}
@@ -0,0 +1,21 @@
+package chatgpt
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+)
+
+// UserIDHasher turns a raw end-user identifier into the opaque pseudonym
+// sent to OpenAI as `user`, so raw PII never leaves the caller's process.
+type UserIDHasher func(id string) string
+
+// DefaultUserIDHasher returns a UserIDHasher that computes
+// base64url(md5(salt+id))[:22], truncated to the 22 characters of
+// unpadded base64url that a 16-byte MD5 sum encodes to. Rotate salt to
+// invalidate every pseudonym derived from it.
+func DefaultUserIDHasher(salt string) UserIDHasher {
+	return func(id string) string {
+		sum := md5.Sum([]byte(salt + id))
+		return base64.RawURLEncoding.EncodeToString(sum[:])[:22]
+	}
+}